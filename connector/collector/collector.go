@@ -0,0 +1,28 @@
+// Package collector gathers per-container metrics for the connectors in
+// github.com/bcicen/ctop/connector and streams them as models.Metrics.
+package collector
+
+import "github.com/bcicen/ctop/models"
+
+// Collector starts, stops, and streams metrics for a single container.
+type Collector interface {
+	Running() bool
+	Start()
+	Stop()
+	Stream() chan models.Metrics
+	Logs() LogCollector
+}
+
+// LogCollector streams a container's log output.
+type LogCollector interface {
+	Stream() chan string
+}
+
+// Extended metric family names, mirroring connector.MetricFamily* — kept
+// as plain strings here (rather than imported) since connector already
+// imports this package.
+const (
+	MetricFamilyPerf = "perf"
+	MetricFamilyRDT  = "rdt"
+	MetricFamilyGPU  = "gpu"
+)
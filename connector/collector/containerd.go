@@ -0,0 +1,173 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bcicen/ctop/models"
+	containerd "github.com/containerd/containerd"
+	cgroupsstats "github.com/containerd/cgroups/stats/v1"
+	"github.com/containerd/typeurl"
+)
+
+// Containerd collects base resource metrics from a containerd task via
+// the Metrics service, plus any extended families (perf/RDT/GPU) enabled
+// via CTOP_METRICS.
+type Containerd struct {
+	id             string
+	client         *containerd.Client
+	families       map[string]bool
+	running        bool
+	stop           chan struct{}
+	stream         chan models.Metrics
+	prevCPUUsage   uint64
+	prevSampleTime time.Time
+}
+
+func NewContainerd(id string, client *containerd.Client, families map[string]bool) Collector {
+	return &Containerd{
+		id:       id,
+		client:   client,
+		families: families,
+		stream:   make(chan models.Metrics),
+	}
+}
+
+func (c *Containerd) Running() bool { return c.running }
+
+func (c *Containerd) Start() {
+	if c.running {
+		return
+	}
+	c.running = true
+	c.stop = make(chan struct{})
+	go c.run()
+}
+
+func (c *Containerd) Stop() {
+	if !c.running {
+		return
+	}
+	c.running = false
+	close(c.stop)
+}
+
+func (c *Containerd) Stream() chan models.Metrics { return c.stream }
+
+func (c *Containerd) Logs() LogCollector { return nil }
+
+func (c *Containerd) run() {
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			m := c.sample()
+			select {
+			case c.stream <- m:
+			case <-c.stop:
+				return
+			}
+		}
+	}
+}
+
+func (c *Containerd) sample() models.Metrics {
+	m := models.NewMetrics()
+	m.Id = c.id
+
+	task := c.loadTask()
+
+	var pid int
+	if task != nil {
+		pid = int(task.Pid())
+		c.fillStats(&m, task)
+	}
+
+	if c.families[MetricFamilyPerf] && pid > 0 {
+		m.Perf = collectPerf([]int{pid})
+	}
+	if c.families[MetricFamilyRDT] {
+		m.RDT = collectRDT(c.id)
+	}
+	if c.families[MetricFamilyGPU] && pid > 0 {
+		m.GPU = collectGPU(c.devicesCgroupPath())
+	}
+
+	return m
+}
+
+// loadTask returns the container's running task, used both for the
+// Metrics service call and as the perf_event_open/devices-cgroup target.
+func (c *Containerd) loadTask() containerd.Task {
+	container, err := c.client.LoadContainer(context.Background(), c.id)
+	if err != nil {
+		return nil
+	}
+	task, err := container.Task(context.Background(), nil)
+	if err != nil {
+		return nil
+	}
+	return task
+}
+
+// fillStats populates the base resource-usage fields (CPU/memory/blkio)
+// by pulling the task's cgroup stats via containerd's Metrics service.
+// CPUUtil is derived from the cumulative usage delta since the last
+// sample, as a percentage of a single core (so it exceeds 100 for as long
+// as more than one core is busy), mirroring the Runc collector's convention.
+func (c *Containerd) fillStats(m *models.Metrics, task containerd.Task) {
+	metric, err := task.Metrics(context.Background())
+	if err != nil {
+		return
+	}
+
+	data, err := typeurl.UnmarshalAny(metric.Data)
+	if err != nil {
+		return
+	}
+
+	stats, ok := data.(*cgroupsstats.Metrics)
+	if !ok || stats.CPU == nil || stats.CPU.Usage == nil {
+		return
+	}
+
+	now := time.Now()
+	usage := stats.CPU.Usage.Total
+	if !c.prevSampleTime.IsZero() {
+		if elapsed := now.Sub(c.prevSampleTime).Nanoseconds(); elapsed > 0 && usage >= c.prevCPUUsage {
+			m.CPUUtil = int((usage - c.prevCPUUsage) * 100 / uint64(elapsed))
+		}
+	}
+	c.prevCPUUsage = usage
+	c.prevSampleTime = now
+
+	if stats.Memory != nil && stats.Memory.Usage != nil {
+		m.MemUsage = int64(stats.Memory.Usage.Usage)
+		m.MemLimit = int64(stats.Memory.Usage.Limit)
+	}
+	if stats.Pids != nil {
+		m.Pids = int(stats.Pids.Current)
+	}
+	if stats.Blkio != nil {
+		for _, entry := range stats.Blkio.IoServiceBytesRecursive {
+			switch entry.Op {
+			case "Read":
+				m.IOBytesRead += int64(entry.Value)
+			case "Write":
+				m.IOBytesWrite += int64(entry.Value)
+			}
+		}
+	}
+}
+
+// devicesCgroupPath guesses the cgroupfs devices-subsystem path shim
+// processes run under; this only holds for the cgroupfs driver, not
+// systemd-managed cgroups.
+func (c *Containerd) devicesCgroupPath() string {
+	return fmt.Sprintf("/sys/fs/cgroup/devices/%s", c.id)
+}
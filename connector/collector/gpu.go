@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/bcicen/ctop/models"
+)
+
+const nvidiaMajor = "195" // fixed major device number for /dev/nvidia<N>
+
+// collectGPU attributes NVIDIA GPU utilization and memory to a container
+// by matching the GPU minor numbers granted in its devices cgroup against
+// NVML device indices, and summing across every GPU it can see.
+func collectGPU(devicesCgroupPath string) *models.GPUMetrics {
+	minors, err := nvidiaMinors(devicesCgroupPath)
+	if err != nil || len(minors) == 0 {
+		return nil
+	}
+
+	if ret := nvml.Init(); ret != nvml.SUCCESS {
+		return nil
+	}
+	defer nvml.Shutdown()
+
+	var gpu models.GPUMetrics
+	var sawAny bool
+	for _, minor := range minors {
+		dev, ret := nvml.DeviceGetHandleByIndex(minor)
+		if ret != nvml.SUCCESS {
+			continue
+		}
+		if util, ret := dev.GetUtilizationRates(); ret == nvml.SUCCESS {
+			gpu.UtilPercent += int(util.Gpu)
+			sawAny = true
+		}
+		if mem, ret := dev.GetMemoryInfo(); ret == nvml.SUCCESS {
+			gpu.MemUsedMiB += mem.Used / (1024 * 1024)
+			gpu.MemTotalMiB += mem.Total / (1024 * 1024)
+			sawAny = true
+		}
+	}
+
+	if !sawAny {
+		return nil
+	}
+	return &gpu
+}
+
+// nvidiaMinors reads a container's devices.list to find which
+// /dev/nvidia<N> device nodes it's been granted access to.
+func nvidiaMinors(devicesCgroupPath string) ([]int, error) {
+	b, err := ioutil.ReadFile(devicesCgroupPath + "/devices.list")
+	if err != nil {
+		return nil, err
+	}
+
+	var minors []int
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		majMin := strings.SplitN(fields[1], ":", 2)
+		if len(majMin) != 2 || majMin[0] != nvidiaMajor {
+			continue
+		}
+		minor, err := strconv.Atoi(majMin[1])
+		if err != nil {
+			continue
+		}
+		minors = append(minors, minor)
+	}
+	if len(minors) == 0 {
+		return nil, fmt.Errorf("no nvidia devices granted")
+	}
+	return minors, nil
+}
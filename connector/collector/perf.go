@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"encoding/binary"
+
+	"github.com/bcicen/ctop/models"
+	"golang.org/x/sys/unix"
+)
+
+// collectPerf opens PERF_TYPE_HARDWARE counters on each of a container's
+// tgids and sums them, giving an approximation of the container's
+// instructions/cycles/cache-misses aggregated across its processes.
+func collectPerf(pids []int) *models.PerfMetrics {
+	if len(pids) == 0 {
+		return nil
+	}
+
+	var perf models.PerfMetrics
+	var sawAny bool
+
+	counters := []struct {
+		config uint64
+		dst    *uint64
+	}{
+		{unix.PERF_COUNT_HW_INSTRUCTIONS, &perf.Instructions},
+		{unix.PERF_COUNT_HW_CPU_CYCLES, &perf.Cycles},
+		{unix.PERF_COUNT_HW_CACHE_MISSES, &perf.CacheMisses},
+		{unix.PERF_COUNT_HW_CACHE_REFERENCES, &perf.LLCLoads},
+	}
+
+	for _, pid := range pids {
+		for _, ctr := range counters {
+			v, err := readPerfCounter(pid, ctr.config)
+			if err != nil {
+				continue
+			}
+			*ctr.dst += v
+			sawAny = true
+		}
+	}
+
+	if !sawAny {
+		return nil
+	}
+	return &perf
+}
+
+func readPerfCounter(pid int, config uint64) (uint64, error) {
+	attr := &unix.PerfEventAttr{
+		Type:   unix.PERF_TYPE_HARDWARE,
+		Config: config,
+		Size:   unix.PERF_ATTR_SIZE_VER0,
+	}
+
+	fd, err := unix.PerfEventOpen(attr, pid, -1, -1, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer unix.Close(fd)
+
+	buf := make([]byte, 8)
+	if _, err := unix.Read(fd, buf); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/bcicen/ctop/models"
+)
+
+// Podman collects base resource metrics from libpod's container stats
+// endpoint. Extended families aren't wired up here (not asked for), only
+// for the Runc/Containerd connectors.
+type Podman struct {
+	id      string
+	client  *http.Client
+	running bool
+	stop    chan struct{}
+	stream  chan models.Metrics
+}
+
+func NewPodman(id string, client *http.Client) Collector {
+	return &Podman{
+		id:     id,
+		client: client,
+		stream: make(chan models.Metrics),
+	}
+}
+
+func (c *Podman) Running() bool { return c.running }
+
+func (c *Podman) Start() {
+	if c.running {
+		return
+	}
+	c.running = true
+	c.stop = make(chan struct{})
+	go c.run()
+}
+
+func (c *Podman) Stop() {
+	if !c.running {
+		return
+	}
+	c.running = false
+	close(c.stop)
+}
+
+func (c *Podman) Stream() chan models.Metrics { return c.stream }
+
+func (c *Podman) Logs() LogCollector { return nil }
+
+func (c *Podman) run() {
+	resp, err := c.client.Get("http://podman/containers/" + c.id + "/stats?stream=true")
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		select {
+		case <-c.stop:
+			return
+		default:
+		}
+
+		var stats struct {
+			CPU      float64 `json:"CPU"`
+			MemUsage int64   `json:"MemUsage"`
+			MemLimit int64   `json:"MemLimit"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &stats); err != nil {
+			continue
+		}
+
+		m := models.NewMetrics()
+		m.Id = c.id
+		m.CPUUtil = int(stats.CPU)
+		m.MemUsage = stats.MemUsage
+		m.MemLimit = stats.MemLimit
+
+		select {
+		case c.stream <- m:
+		case <-c.stop:
+			return
+		case <-time.After(sampleInterval):
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bcicen/ctop/models"
+)
+
+const resctrlMonGroups = "/sys/fs/resctrl/mon_groups"
+
+// collectRDT reads Intel RDT / resctrl occupancy and memory-bandwidth
+// counters for a container's monitoring group, summing across every L3
+// cache domain (mon_L3_00, mon_L3_01, ...) on multi-socket hosts.
+func collectRDT(monGroup string) *models.RDTMetrics {
+	dirs, err := filepath.Glob(filepath.Join(resctrlMonGroups, monGroup, "mon_data", "mon_L3_*"))
+	if err != nil || len(dirs) == 0 {
+		return nil
+	}
+
+	var rdt models.RDTMetrics
+	for _, dir := range dirs {
+		rdt.LLCOccupancy += readUintFile(filepath.Join(dir, "llc_occupancy"))
+		rdt.MBMLocalBytes += readUintFile(filepath.Join(dir, "mbm_local_bytes"))
+		rdt.MBMTotalBytes += readUintFile(filepath.Join(dir, "mbm_total_bytes"))
+	}
+	return &rdt
+}
+
+func readUintFile(path string) uint64 {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	return v
+}
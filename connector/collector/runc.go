@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/bcicen/ctop/models"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+const sampleInterval = 2 * time.Second
+
+// Runc collects base resource metrics from a libcontainer container,
+// plus any extended families (perf/RDT/GPU) enabled via CTOP_METRICS.
+type Runc struct {
+	libc           libcontainer.Container
+	families       map[string]bool
+	running        bool
+	stop           chan struct{}
+	stream         chan models.Metrics
+	prevCPUUsage   uint64
+	prevSampleTime time.Time
+}
+
+func NewRunc(libc libcontainer.Container, families map[string]bool) Collector {
+	return &Runc{
+		libc:     libc,
+		families: families,
+		stream:   make(chan models.Metrics),
+	}
+}
+
+func (c *Runc) Running() bool { return c.running }
+
+func (c *Runc) Start() {
+	if c.running {
+		return
+	}
+	c.running = true
+	c.stop = make(chan struct{})
+	go c.run()
+}
+
+func (c *Runc) Stop() {
+	if !c.running {
+		return
+	}
+	c.running = false
+	close(c.stop)
+}
+
+func (c *Runc) Stream() chan models.Metrics { return c.stream }
+
+func (c *Runc) Logs() LogCollector { return nil }
+
+func (c *Runc) run() {
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			m := c.sample()
+			select {
+			case c.stream <- m:
+			case <-c.stop:
+				return
+			}
+		}
+	}
+}
+
+func (c *Runc) sample() models.Metrics {
+	m := models.NewMetrics()
+	m.Id = c.libc.ID()
+
+	if stats, err := c.libc.Stats(); err == nil {
+		c.fillStats(&m, stats)
+	}
+
+	if c.families[MetricFamilyPerf] {
+		if pids, err := c.libc.Processes(); err == nil {
+			m.Perf = collectPerf(pids)
+		}
+	}
+	if c.families[MetricFamilyRDT] {
+		m.RDT = collectRDT(c.libc.ID())
+	}
+	if c.families[MetricFamilyGPU] {
+		if state, err := c.libc.State(); err == nil {
+			if devicesPath, ok := state.CgroupPaths["devices"]; ok {
+				m.GPU = collectGPU(devicesPath)
+			}
+		}
+	}
+
+	return m
+}
+
+// fillStats populates the base resource-usage fields (CPU/memory/net/blkio)
+// from a libcontainer stats sample. CPUUtil is derived from the cgroup's
+// cumulative usage delta since the last sample, as a percentage of a single
+// core (so it exceeds 100 for as long as more than one core is busy).
+func (c *Runc) fillStats(m *models.Metrics, stats *libcontainer.Stats) {
+	cg := stats.CgroupStats
+	if cg == nil {
+		return
+	}
+
+	now := time.Now()
+	usage := cg.CpuStats.CpuUsage.TotalUsage
+	if !c.prevSampleTime.IsZero() {
+		if elapsed := now.Sub(c.prevSampleTime).Nanoseconds(); elapsed > 0 && usage >= c.prevCPUUsage {
+			m.CPUUtil = int((usage - c.prevCPUUsage) * 100 / uint64(elapsed))
+		}
+	}
+	c.prevCPUUsage = usage
+	c.prevSampleTime = now
+
+	m.MemUsage = int64(cg.MemoryStats.Usage.Usage)
+	m.MemLimit = int64(cg.MemoryStats.Usage.Limit)
+	m.Pids = int(cg.PidsStats.Current)
+	m.IOBytesRead, m.IOBytesWrite = blkioTotals(cg.BlkioStats)
+
+	for _, iface := range stats.Interfaces {
+		m.NetRx += int64(iface.RxBytes)
+		m.NetTx += int64(iface.TxBytes)
+	}
+}
+
+// blkioTotals sums the recursive per-device read/write byte counters into
+// container-wide totals.
+func blkioTotals(stats cgroups.BlkioStats) (read, write int64) {
+	for _, entry := range stats.IoServiceBytesRecursive {
+		switch entry.Op {
+		case "Read":
+			read += int64(entry.Value)
+		case "Write":
+			write += int64(entry.Value)
+		}
+	}
+	return read, write
+}
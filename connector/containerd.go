@@ -0,0 +1,321 @@
+// +build !windows
+
+package connector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/bcicen/ctop/connector/collector"
+	"github.com/bcicen/ctop/entity"
+	"github.com/bcicen/ctop/models"
+	containerd "github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+)
+
+func init() {
+	enabled["containerd"] = NewContainerd
+}
+
+const defaultContainerdSock = "/run/containerd/containerd.sock"
+
+type ContainerdOpts struct {
+	sockPath       string // path to containerd gRPC socket
+	namespace      string // namespace to scope to, empty means all
+	metricFamilies map[string]bool
+}
+
+func NewContainerdOpts() (ContainerdOpts, error) {
+	var opts ContainerdOpts
+
+	sock := os.Getenv("CONTAINERD_SOCK")
+	if sock == "" {
+		sock = defaultContainerdSock
+	}
+	if _, err := os.Stat(sock); err != nil {
+		return opts, fmt.Errorf("containerd socket unreachable: %s", err)
+	}
+	opts.sockPath = sock
+	opts.namespace = os.Getenv("CONTAINERD_NAMESPACE")
+	opts.metricFamilies = ParseMetricFamilies()
+
+	return opts, nil
+}
+
+type Containerd struct {
+	opts         ContainerdOpts
+	client       *containerd.Client
+	containers   map[string]*entity.Container
+	tasks        map[string]*entity.Task
+	needsRefresh chan string // namespaces requiring a refreshNamespace pass
+	events       chan ConnectorEvent
+	lock         sync.RWMutex
+}
+
+func NewContainerd() Connector {
+	opts, err := NewContainerdOpts()
+	if err != nil {
+		return NewMissingRuntime("containerd", err)
+	}
+
+	client, err := containerd.New(opts.sockPath)
+	if err != nil {
+		return NewMissingRuntime("containerd", err)
+	}
+
+	cm := &Containerd{
+		opts:         opts,
+		client:       client,
+		containers:   make(map[string]*entity.Container),
+		tasks:        make(map[string]*entity.Task),
+		needsRefresh: make(chan string, 60),
+		events:       make(chan ConnectorEvent, 60),
+		lock:         sync.RWMutex{},
+	}
+
+	go cm.Loop()
+	go cm.scanExisting() // list already-running tasks before relying on events
+	go cm.watchEvents()
+
+	return cm
+}
+
+// Events returns the channel of task lifecycle changes observed via the
+// containerd events service, satisfying EventSource.
+func (cm *Containerd) Events() <-chan ConnectorEvent {
+	return cm.events
+}
+
+// pushEvent sends a lifecycle event without blocking. Events() is a
+// best-effort stream for the UI to react to, not a critical path like
+// needsRefresh; a full buffer with nothing draining it must never wedge
+// watchEvents.
+func (cm *Containerd) pushEvent(e ConnectorEvent) {
+	select {
+	case cm.events <- e:
+	default:
+		log.Debugf("containerd: dropped event %+v, nothing is draining Events()", e)
+	}
+}
+
+// scanExisting enumerates every configured namespace (honoring
+// CONTAINERD_NAMESPACE via cm.namespaces) and queues each for refresh, so
+// tasks already running at startup appear without waiting for a future
+// lifecycle event. Runs after Loop() is already draining needsRefresh,
+// since a daemon with more namespaces than the channel's buffer would
+// otherwise hang here.
+func (cm *Containerd) scanExisting() {
+	nss, err := cm.namespaces(context.Background())
+	if err != nil {
+		log.Warningf("containerd: failed to list namespaces: %s", err)
+		return
+	}
+	for _, ns := range nss {
+		cm.needsRefresh <- ns
+	}
+}
+
+// namespaces returns the set of namespaces to scan, honoring
+// CONTAINERD_NAMESPACE when set, or every namespace known to the daemon
+// otherwise.
+func (cm *Containerd) namespaces(ctx context.Context) ([]string, error) {
+	if cm.opts.namespace != "" {
+		return []string{cm.opts.namespace}, nil
+	}
+	return cm.client.NamespaceService().List(ctx)
+}
+
+// watchEvents subscribes to the containerd events service, reacting to
+// task lifecycle changes without polling. Each event queues a refresh of
+// the whole namespace, and the ConnectorEvent it pushes carries that
+// namespace as its Id, not the ID of the task that changed.
+func (cm *Containerd) watchEvents() {
+	ctx := context.Background()
+	ch, errs := cm.client.EventService().Subscribe(ctx)
+	for {
+		select {
+		case ev := <-ch:
+			switch ev.Topic {
+			case "/tasks/start", "/tasks/exit", "/tasks/delete", "/tasks/oom":
+				cm.needsRefresh <- ev.Namespace
+				cm.pushEvent(ConnectorEvent{Id: ev.Namespace, Kind: ev.Topic})
+			}
+		case err := <-errs:
+			if err != nil {
+				log.Warningf("containerd: event stream error: %s", err)
+			}
+			return
+		}
+	}
+}
+
+func (cm *Containerd) Loop() {
+	for ns := range cm.needsRefresh {
+		cm.refreshNamespace(ns)
+	}
+}
+
+// refreshNamespace lists the containers in a namespace and updates each
+// in place from its task's status, creating ctop entities for any newly
+// seen task.
+func (cm *Containerd) refreshNamespace(ns string) {
+	ctx := namespacedContext(ns)
+
+	containers, err := cm.client.Containers(ctx)
+	if err != nil {
+		log.Warningf("containerd: failed to list containers in %s: %s", ns, err)
+		return
+	}
+
+	for _, container := range containers {
+		id := container.ID()
+		task, err := container.Task(ctx, nil)
+		if err != nil {
+			// task already exited and was reaped (or never started); reflect
+			// that instead of leaving a stale "running" status in the grid
+			// forever
+			if c, ok := cm.GetContainer(id); ok {
+				c.SetState("stopped")
+			}
+			if t, ok := cm.GetTask(id); ok {
+				t.SetState("stopped")
+			}
+			continue
+		}
+
+		t := cm.mustGetTask(ns, id)
+
+		status, err := task.Status(ctx)
+		if err != nil {
+			log.Warningf("containerd: failed to read task status for %s: %s", id, err)
+			continue
+		}
+		t.SetState(string(status.Status))
+		t.SetMeta("namespace", ns)
+
+		c := cm.MustGet(id)
+		c.SetState(string(status.Status))
+		c.SetMeta("namespace", ns)
+	}
+}
+
+func (cm *Containerd) mustGetTask(ns, id string) *entity.Task {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	t, ok := cm.tasks[id]
+	if !ok {
+		t = entity.NewTask(id)
+		cm.tasks[id] = t
+	}
+	return t
+}
+
+// Get a single ctop container, creating one anew if not already tracked
+func (cm *Containerd) MustGet(id string) *entity.Container {
+	c, ok := cm.GetContainer(id)
+	if !ok {
+		collector := collector.NewContainerd(id, cm.client, cm.opts.metricFamilies)
+		c = entity.NewContainer(id, collector)
+
+		name := id
+		if len(name) > 12 {
+			name = name[0:12]
+		}
+		c.SetMeta("name", name)
+
+		cm.lock.Lock()
+		cm.containers[id] = c
+		cm.lock.Unlock()
+		log.Debugf("containerd: saw new container: %s", id)
+	}
+	return c
+}
+
+func (cm *Containerd) GetContainer(id string) (*entity.Container, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	c, ok := cm.containers[id]
+	return c, ok
+}
+
+func (cm *Containerd) GetTask(id string) (*entity.Task, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	t, ok := cm.tasks[id]
+	return t, ok
+}
+
+func (cm *Containerd) GetService(id string) (s *entity.Service, ok bool) {
+	return s, ok
+}
+
+func (cm *Containerd) GetPod(id string) (p *entity.Pod, ok bool) {
+	return p, ok
+}
+
+func (cm *Containerd) AllNodes() (nodes entity.Nodes) {
+	return nodes
+}
+
+func (cm *Containerd) AllTasks() (tasks entity.Tasks) {
+	cm.lock.Lock()
+	for _, t := range cm.tasks {
+		tasks = append(tasks, t)
+	}
+	cm.lock.Unlock()
+	return tasks
+}
+
+func (cm *Containerd) AllServices() (services entity.Services) {
+	return services
+}
+
+func (cm *Containerd) AllPods() (pods entity.Pods) {
+	return pods
+}
+
+func (cm *Containerd) AllContainers() (containers entity.Containers) {
+	cm.lock.Lock()
+	for _, container := range cm.containers {
+		containers = append(containers, container)
+	}
+	containers.Filter()
+	cm.lock.Unlock()
+	return containers
+}
+
+func (cm *Containerd) Down() {
+	cm.client.Close()
+}
+
+func (cm *Containerd) SetMetrics(metrics models.Metrics) {
+	if cont, ok := cm.GetContainer(metrics.Id); ok {
+		cont.SetMetrics(metrics)
+	}
+}
+
+// Checkpoint and Restore are not yet wired up for the containerd
+// connector; CRIU support there goes through the shim's own checkpoint
+// image format rather than libpod's or runc's.
+func (cm *Containerd) Checkpoint(id string, opts CheckpointOpts) error {
+	return fmt.Errorf("checkpoint not yet supported by the containerd connector")
+}
+
+func (cm *Containerd) Restore(id string, opts CheckpointOpts) error {
+	return fmt.Errorf("restore not yet supported by the containerd connector")
+}
+
+// Remove drops a container from the grid, used by the UI to clean up
+// stale entries without requiring a reachable containerd socket.
+func (cm *Containerd) Remove(id string) error {
+	cm.lock.Lock()
+	delete(cm.containers, id)
+	cm.lock.Unlock()
+	return nil
+}
+
+func namespacedContext(ns string) context.Context {
+	return namespaces.WithNamespace(context.Background(), ns)
+}
@@ -2,6 +2,8 @@ package connector
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/bcicen/ctop/entity"
 	"github.com/bcicen/ctop/logging"
@@ -10,6 +12,13 @@ import (
 
 var log = logging.Init()
 
+// enabled maps connector names, as passed via "--connector", to their
+// constructors. runc is listed here directly; the other connectors
+// register themselves from an init() in their own file.
+var enabled = map[string]func() Connector{
+	"runc": NewRunc,
+}
+
 // ByName return connector via Name from map
 func ByName(s string) (Connector, error) {
 	if _, ok := enabled[s]; !ok {
@@ -28,9 +37,64 @@ type Connector interface {
 	AllServices() entity.Services
 	AllContainers() entity.Containers
 	AllTasks() entity.Tasks
+	AllPods() entity.Pods
 	GetContainer(string) (*entity.Container, bool)
 	GetService(string) (*entity.Service, bool)
 	GetTask(string) (*entity.Task, bool)
+	GetPod(string) (*entity.Pod, bool)
 	Down()
 	SetMetrics(metrics models.Metrics)
+	Checkpoint(id string, opts CheckpointOpts) error
+	Restore(id string, opts CheckpointOpts) error
+	// Remove drops a stale entry (e.g. one left in "unknown-runtime") from
+	// the grid without requiring its runtime to be reachable.
+	Remove(id string) error
+}
+
+// CheckpointOpts configures a CRIU-based checkpoint/restore of a single
+// container, surfaced via a small modal in the expander and bound to the
+// "C" (checkpoint) / "R" (restore) keys on the single-container menu.
+type CheckpointOpts struct {
+	ImagePath      string // directory the checkpoint image is written to/read from
+	TCPEstablished bool   // checkpoint/restore established TCP connections
+	LeaveRunning   bool   // leave the container running after checkpoint
+	PreCheckpoint  bool   // write an incremental pre-dump instead of a full checkpoint
+}
+
+// ConnectorEvent describes a lifecycle change pushed by a connector's
+// native event stream (e.g. a container start, stop or destroy), letting
+// the UI refresh reactively instead of waiting on the next timed poll.
+type ConnectorEvent struct {
+	Id   string // affected container/task ID (the task's namespace, for containerd)
+	Kind string // e.g. "start", "stop", "destroy"
+}
+
+// EventSource is implemented by connectors capable of pushing native
+// lifecycle events. It's optional: connectors without a native event
+// stream simply don't implement it, and callers type-assert for it.
+type EventSource interface {
+	Events() <-chan ConnectorEvent
+}
+
+// Extended metric families a connector's collector may optionally gather,
+// each with a real per-container cost (perf_event_open fds, resctrl reads,
+// NVML calls) that should only be paid when the operator asks for it.
+const (
+	MetricFamilyPerf = "perf" // hardware perf counters (instructions, cycles, cache-misses)
+	MetricFamilyRDT  = "rdt"  // Intel RDT / resctrl (llc_occupancy, mbm_local_bytes, mbm_total_bytes)
+	MetricFamilyGPU  = "gpu"  // NVIDIA GPU utilization/memory via NVML
+)
+
+// ParseMetricFamilies reads the comma-separated CTOP_METRICS env var
+// (e.g. "perf,rdt,gpu") into a lookup set. An unset or empty var means no
+// extended family is collected.
+func ParseMetricFamilies() map[string]bool {
+	families := make(map[string]bool)
+	for _, f := range strings.Split(os.Getenv("CTOP_METRICS"), ",") {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			families[f] = true
+		}
+	}
+	return families
 }
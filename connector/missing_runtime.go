@@ -0,0 +1,88 @@
+package connector
+
+import (
+	"fmt"
+
+	"github.com/bcicen/ctop/connector/collector"
+	"github.com/bcicen/ctop/entity"
+	"github.com/bcicen/ctop/models"
+)
+
+// MissingRuntime is a fallback Connector used when a real connector fails
+// to initialize because its runtime is unreachable (the OCI runtime
+// binary, or its state directory, no longer exists). Rather than
+// panicking and taking down the whole UI, it surfaces a single
+// placeholder entry in the "unknown-runtime" state describing the
+// failure, which the user can Remove once the underlying issue is fixed.
+type MissingRuntime struct {
+	name    string
+	err     error
+	c       *entity.Container
+	removed bool
+}
+
+func NewMissingRuntime(name string, err error) Connector {
+	log.Warningf("%s: runtime unavailable: %s", name, err)
+
+	c := entity.NewContainer(name, noopCollector{})
+	c.SetMeta("name", name)
+	c.SetMeta("error", err.Error())
+	c.SetState("unknown-runtime")
+
+	return &MissingRuntime{name: name, err: err, c: c}
+}
+
+func (cm *MissingRuntime) AllNodes() (nodes entity.Nodes)          { return nodes }
+func (cm *MissingRuntime) AllServices() (services entity.Services) { return services }
+func (cm *MissingRuntime) AllTasks() (tasks entity.Tasks)          { return tasks }
+func (cm *MissingRuntime) AllPods() (pods entity.Pods)             { return pods }
+
+func (cm *MissingRuntime) AllContainers() entity.Containers {
+	if cm.removed {
+		return entity.Containers{}
+	}
+	return entity.Containers{cm.c}
+}
+
+func (cm *MissingRuntime) GetContainer(id string) (*entity.Container, bool) {
+	if cm.removed || id != cm.c.Id {
+		return nil, false
+	}
+	return cm.c, true
+}
+
+func (cm *MissingRuntime) GetService(id string) (s *entity.Service, ok bool) { return s, ok }
+func (cm *MissingRuntime) GetTask(id string) (t *entity.Task, ok bool)       { return t, ok }
+func (cm *MissingRuntime) GetPod(id string) (p *entity.Pod, ok bool)         { return p, ok }
+
+// Remove clears the placeholder entry so a user who has fixed the
+// underlying runtime problem can clean it out of the grid.
+func (cm *MissingRuntime) Remove(id string) error {
+	if id == cm.c.Id {
+		cm.removed = true
+	}
+	return nil
+}
+
+func (cm *MissingRuntime) Down() {}
+
+func (cm *MissingRuntime) SetMetrics(metrics models.Metrics) {}
+
+func (cm *MissingRuntime) Checkpoint(id string, opts CheckpointOpts) error {
+	return fmt.Errorf("%s: checkpoint unavailable, runtime is missing", cm.name)
+}
+
+func (cm *MissingRuntime) Restore(id string, opts CheckpointOpts) error {
+	return fmt.Errorf("%s: restore unavailable, runtime is missing", cm.name)
+}
+
+// noopCollector is a collector.Collector that never runs, used for
+// placeholder entities (unknown-runtime containers) that have nothing to
+// collect metrics from.
+type noopCollector struct{}
+
+func (noopCollector) Running() bool                { return false }
+func (noopCollector) Start()                       {}
+func (noopCollector) Stop()                        {}
+func (noopCollector) Stream() chan models.Metrics  { return nil }
+func (noopCollector) Logs() collector.LogCollector { return nil }
@@ -0,0 +1,392 @@
+// +build !windows
+
+package connector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bcicen/ctop/connector/collector"
+	"github.com/bcicen/ctop/entity"
+	"github.com/bcicen/ctop/models"
+)
+
+func init() {
+	enabled["podman"] = NewPodman
+}
+
+type PodmanOpts struct {
+	sockPath string // path to libpod API socket
+}
+
+func NewPodmanOpts() (PodmanOpts, error) {
+	var opts PodmanOpts
+
+	sock := os.Getenv("PODMAN_SOCK")
+	if sock == "" {
+		sock = defaultPodmanSock()
+	}
+	if _, err := os.Stat(sock); err != nil {
+		return opts, fmt.Errorf("podman socket unreachable: %s", err)
+	}
+	opts.sockPath = sock
+
+	return opts, nil
+}
+
+// defaultPodmanSock resolves the rootless user socket when available,
+// falling back to the system socket used when running as root.
+func defaultPodmanSock() string {
+	if rt := os.Getenv("XDG_RUNTIME_DIR"); rt != "" {
+		return filepath.Join(rt, "podman", "podman.sock")
+	}
+	return "/run/podman/podman.sock"
+}
+
+type Podman struct {
+	opts         PodmanOpts
+	client       *http.Client
+	containers   map[string]*entity.Container
+	pods         map[string]*entity.Pod
+	needsRefresh chan string // container IDs requiring refresh
+	events       chan ConnectorEvent
+	lock         sync.RWMutex
+}
+
+func NewPodman() Connector {
+	opts, err := NewPodmanOpts()
+	if err != nil {
+		return NewMissingRuntime("podman", err)
+	}
+
+	cm := &Podman{
+		opts:         opts,
+		client:       newPodmanClient(opts.sockPath),
+		containers:   make(map[string]*entity.Container),
+		pods:         make(map[string]*entity.Pod),
+		needsRefresh: make(chan string, 60),
+		events:       make(chan ConnectorEvent, 60),
+		lock:         sync.RWMutex{},
+	}
+
+	go cm.Loop()
+	go cm.scanExisting() // list already-running containers/pods before relying on events
+	go cm.watchEvents()
+
+	return cm
+}
+
+// Events returns the channel of container/pod lifecycle changes observed
+// via the libpod events stream, satisfying EventSource.
+func (cm *Podman) Events() <-chan ConnectorEvent {
+	return cm.events
+}
+
+// pushEvent sends a lifecycle event without blocking. Events() is a
+// best-effort stream for the UI to react to, not a critical path like
+// needsRefresh; a full buffer with nothing draining it must never wedge
+// watchEvents.
+func (cm *Podman) pushEvent(e ConnectorEvent) {
+	select {
+	case cm.events <- e:
+	default:
+		log.Debugf("podman: dropped event %+v, nothing is draining Events()", e)
+	}
+}
+
+// scanExisting lists containers and pods already present at startup, so
+// the grid isn't empty until a future lifecycle event happens to fire for
+// them. Runs after Loop() is already draining needsRefresh, since a host
+// with more containers than the channel's buffer would otherwise hang here.
+func (cm *Podman) scanExisting() {
+	var list []struct {
+		Id string `json:"Id"`
+	}
+
+	resp, err := cm.get("/containers/json?all=true")
+	if err != nil {
+		log.Warningf("podman: failed to list containers: %s", err)
+		return
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		log.Warningf("podman: failed to decode container list: %s", err)
+		return
+	}
+
+	for _, c := range list {
+		cm.needsRefresh <- c.Id
+	}
+
+	cm.refreshPods()
+}
+
+// newPodmanClient returns an http.Client dialing the libpod REST API over
+// its unix socket, rootless or not.
+func newPodmanClient(sockPath string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("unix", sockPath)
+			},
+		},
+	}
+}
+
+func (cm *Podman) get(path string) (*http.Response, error) {
+	return cm.client.Get("http://podman" + path)
+}
+
+// watchEvents subscribes to the libpod events stream and queues affected
+// containers for refresh, rather than relying on a poll loop.
+func (cm *Podman) watchEvents() {
+	resp, err := cm.get("/events?stream=true")
+	if err != nil {
+		log.Warningf("podman: failed to subscribe to events: %s", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var ev struct {
+			Type   string `json:"Type"`
+			Status string `json:"Status"`
+			ID     string `json:"ID"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			log.Warningf("podman: malformed event: %s", err)
+			continue
+		}
+		switch ev.Type {
+		case "container":
+			cm.needsRefresh <- ev.ID
+			cm.pushEvent(ConnectorEvent{Id: ev.ID, Kind: ev.Status})
+		case "pod":
+			cm.refreshPods()
+		}
+	}
+}
+
+func (cm *Podman) Loop() {
+	for id := range cm.needsRefresh {
+		cm.refresh(id)
+	}
+}
+
+// update a ctop container from a libpod container inspect
+func (cm *Podman) refresh(id string) {
+	var inspect struct {
+		State struct {
+			Status string `json:"Status"`
+			Health struct {
+				Status string `json:"Status"` // starting, healthy, unhealthy, or "" when unset
+				Log    []struct {
+					Start    string `json:"Start"` // RFC3339Nano
+					ExitCode int    `json:"ExitCode"`
+					Output   string `json:"Output"`
+				} `json:"Log"`
+			} `json:"Health"`
+		} `json:"State"`
+		Config struct {
+			Healthcheck struct {
+				Test []string `json:"Test"`
+			} `json:"Healthcheck"`
+		} `json:"Config"`
+		Pod string `json:"Pod"`
+	}
+
+	resp, err := cm.get("/containers/" + id + "/json")
+	if err != nil {
+		log.Warningf("podman: failed to inspect container %s: %s", id, err)
+		return
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&inspect); err != nil {
+		log.Warningf("podman: failed to decode inspect for %s: %s", id, err)
+		return
+	}
+
+	c := cm.MustGet(id)
+	c.SetState(inspect.State.Status)
+	if inspect.Pod != "" {
+		c.SetMeta("pod", inspect.Pod)
+	}
+
+	if len(inspect.Config.Healthcheck.Test) > 0 {
+		health := inspect.State.Health.Status
+		if health == "" {
+			health = "starting"
+		}
+		c.SetHealth(health)
+
+		results := make([]entity.HealthCheckResult, 0, len(inspect.State.Health.Log))
+		for _, entry := range inspect.State.Health.Log {
+			ts, _ := time.Parse(time.RFC3339Nano, entry.Start)
+			results = append(results, entity.HealthCheckResult{
+				Timestamp: ts,
+				ExitCode:  entry.ExitCode,
+				Output:    entry.Output,
+			})
+		}
+		c.SetHealthHistory(results)
+	}
+}
+
+func (cm *Podman) refreshPods() {
+	var list []struct {
+		Id string `json:"Id"`
+	}
+
+	resp, err := cm.get("/pods/json")
+	if err != nil {
+		log.Warningf("podman: failed to list pods: %s", err)
+		return
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		log.Warningf("podman: failed to decode pod list: %s", err)
+		return
+	}
+
+	cm.lock.Lock()
+	for _, p := range list {
+		if _, ok := cm.pods[p.Id]; !ok {
+			cm.pods[p.Id] = entity.NewPod(p.Id)
+		}
+	}
+	cm.lock.Unlock()
+}
+
+// Get a single ctop container, creating one anew if not already tracked
+func (cm *Podman) MustGet(id string) *entity.Container {
+	c, ok := cm.GetContainer(id)
+	if !ok {
+		collector := collector.NewPodman(id, cm.client)
+		c = entity.NewContainer(id, collector)
+
+		name := id
+		if len(name) > 12 {
+			name = name[0:12]
+		}
+		c.SetMeta("name", name)
+
+		cm.lock.Lock()
+		cm.containers[id] = c
+		cm.lock.Unlock()
+		log.Debugf("podman: saw new container: %s", id)
+	}
+	return c
+}
+
+func (cm *Podman) GetContainer(id string) (*entity.Container, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	c, ok := cm.containers[id]
+	return c, ok
+}
+
+func (cm *Podman) GetPod(id string) (*entity.Pod, bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	p, ok := cm.pods[id]
+	return p, ok
+}
+
+func (cm *Podman) GetTask(id string) (t *entity.Task, ok bool) {
+	return t, ok
+}
+
+func (cm *Podman) GetService(id string) (s *entity.Service, ok bool) {
+	return s, ok
+}
+
+func (cm *Podman) AllNodes() (nodes entity.Nodes) {
+	return nodes
+}
+
+func (cm *Podman) AllTasks() (tasks entity.Tasks) {
+	return tasks
+}
+
+func (cm *Podman) AllServices() (services entity.Services) {
+	return services
+}
+
+func (cm *Podman) AllPods() (pods entity.Pods) {
+	cm.lock.Lock()
+	for _, p := range cm.pods {
+		pods = append(pods, p)
+	}
+	cm.lock.Unlock()
+	return pods
+}
+
+func (cm *Podman) AllContainers() (containers entity.Containers) {
+	cm.lock.Lock()
+	for _, container := range cm.containers {
+		containers = append(containers, container)
+	}
+	containers.Filter()
+	cm.lock.Unlock()
+	return containers
+}
+
+func (cm *Podman) Down() {
+	log.Warningf("Call unsupported method, Down()")
+}
+
+func (cm *Podman) SetMetrics(metrics models.Metrics) {
+	if cont, ok := cm.GetContainer(metrics.Id); ok {
+		cont.SetMetrics(metrics)
+	}
+}
+
+// Checkpoint calls libpod's container checkpoint endpoint, which drives
+// CRIU on the server side.
+func (cm *Podman) Checkpoint(id string, opts CheckpointOpts) error {
+	return cm.criuRequest(id, "checkpoint", opts)
+}
+
+// Restore calls libpod's container restore endpoint.
+func (cm *Podman) Restore(id string, opts CheckpointOpts) error {
+	return cm.criuRequest(id, "restore", opts)
+}
+
+// Remove drops a container from the grid, used by the UI to clean up
+// stale entries (e.g. unknown-runtime) without requiring a reachable
+// libpod socket.
+func (cm *Podman) Remove(id string) error {
+	cm.lock.Lock()
+	delete(cm.containers, id)
+	cm.lock.Unlock()
+	return nil
+}
+
+func (cm *Podman) criuRequest(id, action string, opts CheckpointOpts) error {
+	path := fmt.Sprintf("/containers/%s/%s?export=%s&tcpEstablished=%t&leaveRunning=%t&preCheckpoint=%t",
+		id, action, opts.ImagePath, opts.TCPEstablished, opts.LeaveRunning, opts.PreCheckpoint)
+
+	resp, err := cm.client.Post("http://podman"+path, "application/json", nil)
+	if err != nil {
+		err = fmt.Errorf("%s failed for %s: %s", action, id, err)
+		cm.MustGet(id).SetMeta("last_error", err.Error())
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		err = fmt.Errorf("%s failed for %s: server returned %s", action, id, resp.Status)
+		cm.MustGet(id).SetMeta("last_error", err.Error())
+		return err
+	}
+	return nil
+}
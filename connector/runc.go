@@ -8,18 +8,25 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/bcicen/ctop/connector/collector"
-	"github.com/bcicen/ctop/connector/manager"
 	"github.com/bcicen/ctop/entity"
 	"github.com/bcicen/ctop/models"
+	"github.com/fsnotify/fsnotify"
 	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/runc/libcontainer/cgroups/systemd"
 )
 
+// fallbackScanInterval is the coarse, timed re-scan of the runc root kept
+// as a correctness net alongside the inotify watches, in case a watch is
+// dropped (e.g. the host's inotify instance limit is hit).
+const fallbackScanInterval = 30 * time.Second
+
 type RuncOpts struct {
 	root           string // runc root path
 	systemdCgroups bool   // use systemd cgroups
+	metricFamilies map[string]bool
 }
 
 func NewRuncOpts() (RuncOpts, error) {
@@ -44,6 +51,11 @@ func NewRuncOpts() (RuncOpts, error) {
 	if os.Getenv("RUNC_SYSTEMD_CGROUP") == "1" {
 		opts.systemdCgroups = true
 	}
+
+	// extended metric families (perf/rdt/gpu) are opt-in: each carries a
+	// real per-container collection cost, so none run unless asked for
+	opts.metricFamilies = ParseMetricFamilies()
+
 	return opts, nil
 }
 
@@ -53,15 +65,30 @@ type Runc struct {
 	containers    map[string]*entity.Container
 	libContainers map[string]libcontainer.Container
 	needsRefresh  chan string // container IDs requiring refresh
+	events        chan ConnectorEvent
+	rootWatcher   *fsnotify.Watcher
+	stateWatchers map[string]*fsnotify.Watcher // per-container state.json watchers
 	lock          sync.RWMutex
 }
 
 func NewRunc() Connector {
 	opts, err := NewRuncOpts()
-	runcFailOnErr(err)
+	if err != nil {
+		return NewMissingRuntime("runc", err)
+	}
 
 	factory, err := getFactory(opts)
-	runcFailOnErr(err)
+	if err != nil {
+		return NewMissingRuntime("runc", err)
+	}
+
+	rootWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return NewMissingRuntime("runc", err)
+	}
+	if err := rootWatcher.Add(opts.root); err != nil {
+		return NewMissingRuntime("runc", err)
+	}
 
 	cm := &Runc{
 		opts:          opts,
@@ -69,13 +96,122 @@ func NewRunc() Connector {
 		containers:    make(map[string]*entity.Container),
 		libContainers: make(map[string]libcontainer.Container),
 		needsRefresh:  make(chan string, 60),
+		events:        make(chan ConnectorEvent, 60),
+		rootWatcher:   rootWatcher,
+		stateWatchers: make(map[string]*fsnotify.Watcher),
 		lock:          sync.RWMutex{},
 	}
+	// start the consumer before the initial scan: scanRoot queues one
+	// needsRefresh entry per discovered container, which would block on
+	// the buffered channel (and hang startup) on a host with more
+	// containers than the buffer if nothing were draining it yet
 	go cm.Loop()
+	go cm.watchRoot()
+	go cm.fallbackScan()
+
+	cm.scanRoot() // initial population, before watches can have fired
 
 	return cm
 }
 
+// Events returns the channel of container lifecycle changes observed via
+// inotify, satisfying EventSource.
+func (cm *Runc) Events() <-chan ConnectorEvent {
+	return cm.events
+}
+
+// watchRoot reacts to container directories appearing or disappearing
+// under the runc root, rather than waiting on the next timed scan.
+func (cm *Runc) watchRoot() {
+	for {
+		select {
+		case ev, ok := <-cm.rootWatcher.Events:
+			if !ok {
+				return
+			}
+			id := filepath.Base(ev.Name)
+			switch {
+			case ev.Op&fsnotify.Create != 0:
+				cm.watchContainerState(id)
+				cm.needsRefresh <- id
+			case ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+				cm.stopWatchingContainerState(id)
+				cm.delByID(id)
+				cm.pushEvent(ConnectorEvent{Id: id, Kind: "destroy"})
+			}
+		case err, ok := <-cm.rootWatcher.Errors:
+			if !ok {
+				return
+			}
+			log.Warningf("runc: root watch error: %s", err)
+		}
+	}
+}
+
+// watchContainerState watches a single container's state.json, pushing a
+// refresh as soon as runc records a status transition rather than
+// discovering it on the next scan.
+func (cm *Runc) watchContainerState(id string) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	if _, ok := cm.stateWatchers[id]; ok {
+		return
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Warningf("runc: failed to watch state for %s: %s", id, err)
+		return
+	}
+	if err := w.Add(filepath.Join(cm.opts.root, id)); err != nil {
+		log.Warningf("runc: failed to watch state dir for %s: %s", id, err)
+		w.Close()
+		return
+	}
+	cm.stateWatchers[id] = w
+
+	go func() {
+		for ev := range w.Events {
+			if filepath.Base(ev.Name) != "state.json" {
+				continue
+			}
+			cm.needsRefresh <- id
+			cm.pushEvent(ConnectorEvent{Id: id, Kind: "update"})
+		}
+	}()
+}
+
+// pushEvent sends a lifecycle event without blocking. Events() is a
+// best-effort stream for the UI to react to, not a critical path like
+// needsRefresh; a full buffer with nothing draining it must never wedge
+// the goroutines that produce it (Loop, watchRoot, the state watchers).
+func (cm *Runc) pushEvent(e ConnectorEvent) {
+	select {
+	case cm.events <- e:
+	default:
+		log.Debugf("runc: dropped event %+v, nothing is draining Events()", e)
+	}
+}
+
+func (cm *Runc) stopWatchingContainerState(id string) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	if w, ok := cm.stateWatchers[id]; ok {
+		w.Close()
+		delete(cm.stateWatchers, id)
+	}
+}
+
+// fallbackScan keeps a coarse timed re-scan running alongside the inotify
+// watches, as a correctness net for missed or dropped events.
+func (cm *Runc) fallbackScan() {
+	ticker := time.NewTicker(fallbackScanInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		cm.scanRoot()
+	}
+}
+
 func (cm *Runc) GetLibc(id string) libcontainer.Container {
 	// return previously loaded container
 	libc, ok := cm.libContainers[id]
@@ -89,7 +225,13 @@ func (cm *Runc) GetLibc(id string) libcontainer.Container {
 		if lerr, ok := err.(libcontainer.Error); ok && lerr.Code() == libcontainer.ContainerNotExists {
 			cm.delByID(id)
 		} else {
+			// state dir is present but the runtime behind it (binary or
+			// cgroup) is gone; keep the entry visible rather than losing
+			// track of it
 			log.Warningf("failed to read container: %s\n", err)
+			if c, ok := cm.GetContainer(id); ok {
+				c.SetState("unknown-runtime")
+			}
 		}
 		return nil
 	}
@@ -115,7 +257,11 @@ func (cm *Runc) refresh(id string) {
 	if err != nil {
 		log.Warningf("failed to read status for container: %s\n", err)
 	} else {
+		prev := c.GetMeta("state")
 		c.SetState(status.String())
+		if prev != status.String() {
+			cm.pushEvent(ConnectorEvent{Id: id, Kind: status.String()})
+		}
 	}
 
 	state, err := libc.State()
@@ -129,10 +275,17 @@ func (cm *Runc) refresh(id string) {
 	c.SetMeta("rootfs", conf.Rootfs)
 }
 
-// Read runc root, creating any new containers
-func (cm *Runc) refreshAllContainers() {
+// scanRoot reads the runc root, creating any new containers and arming a
+// state-file watch for each. This is the event-driven path's initial
+// population, and doubles as the coarse fallback scan.
+func (cm *Runc) scanRoot() {
 	list, err := ioutil.ReadDir(cm.opts.root)
-	runcFailOnErr(err)
+	if err != nil {
+		// a single unreadable root shouldn't take down the whole UI; skip
+		// this scan and let the fallback timer retry
+		log.Warningf("runc: failed to read root %s: %s\n", cm.opts.root, err)
+		return
+	}
 
 	for _, i := range list {
 		if i.IsDir() {
@@ -140,9 +293,13 @@ func (cm *Runc) refreshAllContainers() {
 			// attempt to load
 			libc := cm.GetLibc(name)
 			if libc == nil {
+				if _, ok := cm.GetContainer(name); !ok {
+					cm.reportMissingRuntime(name)
+				}
 				continue
 			}
 			_ = cm.MustGet(i.Name()) // ensure container exists
+			cm.watchContainerState(name)
 		}
 	}
 
@@ -165,12 +322,11 @@ func (cm *Runc) MustGet(id string) *entity.Container {
 	if !ok {
 		libc := cm.GetLibc(id)
 
-		// create collector
-		collector := collector.NewRunc(libc)
+		// create collector, enabling any opted-in extended metric families
+		collector := collector.NewRunc(libc, cm.opts.metricFamilies)
 
 		// create container
-		manager := manager.NewRunc()
-		c = entity.NewContainer(id, collector, manager)
+		c = entity.NewContainer(id, collector)
 
 		name := libc.ID()
 		// set initial metadata
@@ -215,6 +371,31 @@ func (cm *Runc) delByID(id string) {
 	log.Infof("removed dead container: %s", id)
 }
 
+// reportMissingRuntime creates a placeholder entry for a state directory
+// whose runtime (binary or cgroup) can't be loaded, so a broken container
+// stays visible and removable instead of being silently skipped.
+func (cm *Runc) reportMissingRuntime(id string) {
+	c := entity.NewContainer(id, noopCollector{})
+	name := id
+	if len(name) > 12 {
+		name = name[0:12]
+	}
+	c.SetMeta("name", name)
+	c.SetState("unknown-runtime")
+
+	cm.lock.Lock()
+	cm.containers[id] = c
+	cm.lock.Unlock()
+	log.Warningf("runc: %s references a missing or unreadable runtime", id)
+}
+
+// Remove drops a container from the grid, used by the UI to clean up
+// stale unknown-runtime entries once the underlying issue is resolved.
+func (cm *Runc) Remove(id string) error {
+	cm.delByID(id)
+	return nil
+}
+
 func (cm *Runc) AllNodes() (nodes entity.Nodes) {
 	return nodes
 }
@@ -227,6 +408,14 @@ func (cm *Runc) AllServices() (services entity.Services) {
 	return services
 }
 
+func (cm *Runc) AllPods() (pods entity.Pods) {
+	return pods
+}
+
+func (cm *Runc) GetPod(id string) (p *entity.Pod, ok bool) {
+	return p, ok
+}
+
 func (cm *Runc) AllContainers() (containers entity.Containers) {
 	cm.lock.Lock()
 	for _, container := range cm.containers {
@@ -249,12 +438,6 @@ func getFactory(opts RuncOpts) (libcontainer.Factory, error) {
 	return libcontainer.New(opts.root, cgroupManager)
 }
 
-func runcFailOnErr(err error) {
-	if err != nil {
-		panic(fmt.Errorf("fatal runc error: %s", err))
-	}
-}
-
 func (cm *Runc) Down() {
 	log.Warningf("Call unsupported method, Down()")
 }
@@ -264,3 +447,46 @@ func (cm *Runc) SetMetrics(metrics models.Metrics) {
 		cont.SetMetrics(metrics)
 	}
 }
+
+// Checkpoint freezes a running container's state to opts.ImagePath via
+// CRIU, leaving it running or stopped depending on opts.LeaveRunning.
+// CRIU failures are reported back to the caller rather than panicking, so
+// they can be surfaced in the container's log pane.
+func (cm *Runc) Checkpoint(id string, opts CheckpointOpts) error {
+	libc := cm.GetLibc(id)
+	if libc == nil {
+		return fmt.Errorf("no such container: %s", id)
+	}
+
+	err := libc.Checkpoint(&libcontainer.CriuOpts{
+		ImagesDirectory: opts.ImagePath,
+		WorkDirectory:   opts.ImagePath,
+		TcpEstablished:  opts.TCPEstablished,
+		LeaveRunning:    opts.LeaveRunning,
+		PreDump:         opts.PreCheckpoint,
+	})
+	if err != nil {
+		err = fmt.Errorf("checkpoint failed for %s: %s", id, err)
+		cm.MustGet(id).SetMeta("last_error", err.Error())
+	}
+	return err
+}
+
+// Restore resumes a container previously checkpointed to opts.ImagePath.
+func (cm *Runc) Restore(id string, opts CheckpointOpts) error {
+	libc := cm.GetLibc(id)
+	if libc == nil {
+		return fmt.Errorf("no such container: %s", id)
+	}
+
+	err := libc.Restore(&libcontainer.Process{}, &libcontainer.CriuOpts{
+		ImagesDirectory: opts.ImagePath,
+		WorkDirectory:   opts.ImagePath,
+		TcpEstablished:  opts.TCPEstablished,
+	})
+	if err != nil {
+		err = fmt.Errorf("restore failed for %s: %s", id, err)
+		cm.MustGet(id).SetMeta("last_error", err.Error())
+	}
+	return err
+}
@@ -14,11 +14,67 @@ type GridCursor struct {
 	filteredNodes      entity.Nodes
 	filteredServices   entity.Services
 	cSource            connector.Connector
-	isScrolling        bool // toggled when actively scrolling
+	isScrolling        bool   // toggled when actively scrolling
+	healthFilter       string // if set, only containers with this health status are shown
+}
+
+// SetHealthFilter sets or clears (pass "") the healthcheck status
+// containers must match to be displayed, bound to the "f h" key combo.
+func (gc *GridCursor) SetHealthFilter(health string) {
+	gc.healthFilter = health
+	gc.RefreshContainers()
 }
 
 func (gc *GridCursor) Len() int { return len(gc.filteredNodes) }
 
+// WatchConnectorEvents selects on the active connector's native event
+// stream, if it implements one, refreshing the grid as soon as a
+// container's lifecycle changes instead of waiting on the next timed poll.
+func (gc *GridCursor) WatchConnectorEvents() {
+	es, ok := gc.cSource.(connector.EventSource)
+	if !ok {
+		return
+	}
+	go func() {
+		for range es.Events() {
+			if gc.RefreshContainers() {
+				ui.Render(cGrid)
+			}
+		}
+	}()
+}
+
+// Checkpoint freezes the selected container's state via the active
+// connector, bound to the "C" key on the single-container menu.
+func (gc *GridCursor) Checkpoint(opts connector.CheckpointOpts) error {
+	c := gc.SelectedContainer()
+	if c == nil {
+		return nil
+	}
+	return gc.cSource.Checkpoint(c.Id, opts)
+}
+
+// Restore resumes the selected container from a prior checkpoint via the
+// active connector, bound to the "R" key on the single-container menu.
+func (gc *GridCursor) Restore(opts connector.CheckpointOpts) error {
+	c := gc.SelectedContainer()
+	if c == nil {
+		return nil
+	}
+	return gc.cSource.Restore(c.Id, opts)
+}
+
+// RemoveSelected drops the selected container from the grid via the
+// active connector, used to clean up stale entries (e.g. unknown-runtime)
+// that no longer need a reachable runtime to be removed.
+func (gc *GridCursor) RemoveSelected() error {
+	c := gc.SelectedContainer()
+	if c == nil {
+		return nil
+	}
+	return gc.cSource.Remove(c.Id)
+}
+
 func (gc *GridCursor) Selected() (entity.Entity, string) {
 	idx, type_entity := gc.Idx()
 	if idx < gc.Len() {
@@ -72,12 +128,16 @@ func (gc *GridCursor) RefreshContainers() (lenChanged bool) {
 	gc.filteredContainers = entity.Containers{}
 	var cursorVisible bool
 	for _, c := range gc.cSource.AllContainers() {
-		if c.Display {
-			if c.Id == gc.selectedID {
-				cursorVisible = true
-			}
-			gc.filteredContainers = append(gc.filteredContainers, c)
+		if !c.Display {
+			continue
+		}
+		if gc.healthFilter != "" && c.GetHealth() != gc.healthFilter {
+			continue
+		}
+		if c.Id == gc.selectedID {
+			cursorVisible = true
 		}
+		gc.filteredContainers = append(gc.filteredContainers, c)
 	}
 
 	if oldLen != gc.Len() {
@@ -1,16 +1,31 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/bcicen/ctop/connector/collector"
 	"github.com/bcicen/ctop/models"
 )
 
+// maxHealthHistory caps how many past healthcheck runs the expander shows
+// alongside the current status dot.
+const maxHealthHistory = 5
+
+// HealthCheckResult is a single OCI healthcheck run, as recorded by the
+// connector from the container runtime's healthcheck log.
+type HealthCheckResult struct {
+	Timestamp time.Time
+	ExitCode  int
+	Output    string
+}
+
 // Metrics and metadata representing a container
 type Container struct {
 	models.Metrics
 	Meta
-	Id        string
-	collector collector.Collector
+	Id            string
+	collector     collector.Collector
+	healthHistory []HealthCheckResult
 }
 
 func NewContainer(id string, collector collector.Collector) *Container {
@@ -59,6 +74,10 @@ func (c *Container) Read(stream chan models.Metrics) {
 		for metrics := range stream {
 			c.Metrics = metrics
 			c.Meta.updater.SetMetrics(metrics)
+			if emu, ok := c.Meta.updater.(ExtendedMetricsUpdater); ok {
+				perf, rdt, gpu := c.ExtendedMetrics()
+				emu.SetExtendedMetrics(perf, rdt, gpu)
+			}
 		}
 		log.Infof("reader stopped for container: %s", c.Id)
 		c.Metrics = models.NewMetrics()
@@ -70,3 +89,73 @@ func (c *Container) Read(stream chan models.Metrics) {
 func (c *Container) GetMeta(v string) string {
 	return c.Meta.GetMeta(v)
 }
+
+// SetHealth records the container's OCI healthcheck status (starting,
+// healthy, unhealthy, or none), as reported by the connector, and pushes
+// the status dot color to the widget updater if it supports rendering one.
+func (c *Container) SetHealth(val string) {
+	c.Meta.SetMeta("health", val)
+	if hu, ok := c.Meta.updater.(HealthUpdater); ok {
+		hu.SetHealth(HealthColor(val), c.healthHistory)
+	}
+}
+
+func (c *Container) GetHealth() string {
+	return c.Meta.GetMeta("health")
+}
+
+// SetHealthHistory records the most recent healthcheck runs (most recent
+// last), trimmed to maxHealthHistory, for the expander to list alongside
+// the current status dot.
+func (c *Container) SetHealthHistory(results []HealthCheckResult) {
+	if len(results) > maxHealthHistory {
+		results = results[len(results)-maxHealthHistory:]
+	}
+	c.healthHistory = results
+	if hu, ok := c.Meta.updater.(HealthUpdater); ok {
+		hu.SetHealth(HealthColor(c.GetHealth()), c.healthHistory)
+	}
+}
+
+func (c *Container) GetHealthHistory() []HealthCheckResult {
+	return c.healthHistory
+}
+
+// HealthUpdater is implemented by a widget updater that renders a
+// container's healthcheck status as a compact grid dot and an expander
+// history list. It's optional: updaters that don't render health simply
+// don't implement it, and Container type-asserts for it before calling.
+type HealthUpdater interface {
+	SetHealth(color string, history []HealthCheckResult)
+}
+
+// ExtendedMetricsUpdater is implemented by a widget updater that renders
+// opted-in perf/RDT/GPU metric families (via CTOP_METRICS) as expander
+// sparkline rows. It's optional: updaters that don't render them simply
+// don't implement it, and Container type-asserts for it before calling.
+type ExtendedMetricsUpdater interface {
+	SetExtendedMetrics(perf *models.PerfMetrics, rdt *models.RDTMetrics, gpu *models.GPUMetrics)
+}
+
+// HealthColor maps a container's healthcheck status to the compact grid's
+// dot color: green (healthy), yellow (starting), red (unhealthy), or grey
+// (no healthcheck configured / unknown).
+func HealthColor(status string) string {
+	switch status {
+	case "healthy":
+		return "green"
+	case "starting":
+		return "yellow"
+	case "unhealthy":
+		return "red"
+	default:
+		return "grey"
+	}
+}
+
+// ExtendedMetrics returns the opted-in perf/RDT/GPU metric families for
+// this container's latest sample, for a widget to render as sparkline
+// rows; each is nil when its family wasn't enabled via CTOP_METRICS.
+func (c *Container) ExtendedMetrics() (*models.PerfMetrics, *models.RDTMetrics, *models.GPUMetrics) {
+	return c.Metrics.Perf, c.Metrics.RDT, c.Metrics.GPU
+}
@@ -0,0 +1,30 @@
+package entity
+
+// Pod groups a set of containers sharing a network/IPC namespace, as
+// managed by Podman's libpod. Containers belonging to the same pod are
+// rendered together in the grid.
+type Pod struct {
+	Meta
+	Id string
+}
+
+func NewPod(id string) *Pod {
+	return &Pod{
+		Meta: NewMeta(id),
+		Id:   id,
+	}
+}
+
+func (p *Pod) GetId() string {
+	return p.Id
+}
+
+func (p *Pod) GetMetaEntity() Meta {
+	return p.Meta
+}
+
+func (p *Pod) GetMeta(v string) string {
+	return p.Meta.GetMeta(v)
+}
+
+type Pods []*Pod
@@ -0,0 +1,50 @@
+package models
+
+// Metrics holds a single sample of a container's resource usage, plus any
+// opted-in extended metric families (perf/RDT/GPU). Extended fields are
+// nil unless their family is enabled via CTOP_METRICS, so collectors and
+// widgets that don't care about them can ignore them for free.
+type Metrics struct {
+	Id           string
+	CPUUtil      int // percent of a single core (exceeds 100 while more than one core is busy)
+	MemUsage     int64
+	MemLimit     int64
+	NetRx        int64
+	NetTx        int64
+	IOBytesRead  int64
+	IOBytesWrite int64
+	Pids         int
+
+	Perf *PerfMetrics // hardware perf counters, enabled via CTOP_METRICS=perf
+	RDT  *RDTMetrics  // Intel RDT / resctrl stats, enabled via CTOP_METRICS=rdt
+	GPU  *GPUMetrics  // NVIDIA GPU stats, enabled via CTOP_METRICS=gpu
+}
+
+// PerfMetrics are hardware performance counters aggregated across a
+// container's cgroup, gathered via perf_event_open(PERF_TYPE_HARDWARE).
+type PerfMetrics struct {
+	Instructions uint64
+	Cycles       uint64
+	CacheMisses  uint64
+	LLCLoads     uint64
+}
+
+// RDTMetrics are Intel RDT / resctrl cache and memory-bandwidth stats,
+// read from /sys/fs/resctrl/mon_groups/<container>/mon_data/mon_L3_*/.
+type RDTMetrics struct {
+	LLCOccupancy  uint64 // bytes
+	MBMLocalBytes uint64
+	MBMTotalBytes uint64
+}
+
+// GPUMetrics are NVIDIA GPU stats attributed to a container by matching
+// its devices cgroup to GPU minor numbers, gathered via NVML.
+type GPUMetrics struct {
+	UtilPercent int
+	MemUsedMiB  uint64
+	MemTotalMiB uint64
+}
+
+func NewMetrics() Metrics {
+	return Metrics{}
+}